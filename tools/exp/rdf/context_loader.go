@@ -0,0 +1,169 @@
+package rdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ContextLoader fetches the JSON-LD context document referenced by an IRI
+// that is not already known to an RDFRegistry. It allows ParseVocabulary to
+// understand @context entries published by extension vocabularies without
+// requiring every such vocabulary to be registered ahead of time.
+type ContextLoader interface {
+	// Load fetches and decodes the JSON-LD context document located at iri.
+	Load(iri string) (JSONLD, error)
+}
+
+// NoRemoteLoader refuses to resolve any remote @context IRI. It is the
+// correct choice for hermetic builds -- tests, CI, or anywhere a network
+// fetch would be inappropriate -- and is the default for a new RDFRegistry.
+type NoRemoteLoader struct{}
+
+func (NoRemoteLoader) Load(iri string) (JSONLD, error) {
+	return nil, fmt.Errorf("remote @context resolution is disabled: %s", iri)
+}
+
+// OfflineContextLoader serves a fixed set of well-known JSON-LD context
+// documents bundled with this module, without touching the network. It is
+// seeded with the ActivityStreams and W3ID security/identity contexts that
+// most ActivityPub implementations declare.
+type OfflineContextLoader struct {
+	known map[string]JSONLD
+}
+
+// NewOfflineContextLoader creates an OfflineContextLoader seeded with the
+// well-known contexts this module ships with.
+func NewOfflineContextLoader() *OfflineContextLoader {
+	o := &OfflineContextLoader{known: make(map[string]JSONLD, len(bundledContexts))}
+	for iri, doc := range bundledContexts {
+		o.known[iri] = doc
+	}
+	return o
+}
+
+// Seed registers an additional context document for iri, overwriting any
+// bundled document already present under that IRI.
+func (o *OfflineContextLoader) Seed(iri string, doc JSONLD) {
+	o.known[iri] = doc
+}
+
+func (o *OfflineContextLoader) Load(iri string) (JSONLD, error) {
+	doc, ok := o.known[iri]
+	if !ok {
+		return nil, fmt.Errorf("no offline copy of @context %q is bundled", iri)
+	}
+	return doc, nil
+}
+
+// bundledContexts holds the well-known JSON-LD @context documents shipped
+// with this module so that OfflineContextLoader can serve them without
+// network access.
+var bundledContexts = map[string]JSONLD{
+	"https://www.w3.org/ns/activitystreams": {
+		"@context": JSONLD{
+			"@vocab": "https://www.w3.org/ns/activitystreams#",
+		},
+	},
+	"https://w3id.org/security/v1": {
+		"@context": JSONLD{
+			"@vocab": "https://w3id.org/security#",
+		},
+	},
+	"https://w3id.org/identity/v1": {
+		"@context": JSONLD{
+			"@vocab": "https://w3id.org/identity#",
+		},
+	},
+}
+
+// HTTPContextLoader fetches @context documents over HTTP(S) and caches them
+// on disk, keyed by the IRI and the response's ETag, so repeated parses of
+// the same extension vocabulary do not re-fetch it every time.
+type HTTPContextLoader struct {
+	Client   *http.Client
+	CacheDir string
+}
+
+// NewHTTPContextLoader creates an HTTPContextLoader that caches fetched
+// documents under cacheDir. If client is nil, http.DefaultClient is used.
+func NewHTTPContextLoader(cacheDir string, client *http.Client) *HTTPContextLoader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPContextLoader{Client: client, CacheDir: cacheDir}
+}
+
+func (h *HTTPContextLoader) Load(iri string) (JSONLD, error) {
+	docPath, etagPath := h.cachePaths(iri)
+	req, err := http.NewRequest(http.MethodGet, iri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("loading @context %q: %w", iri, err)
+	}
+	if etag, err := ioutil.ReadFile(etagPath); err == nil {
+		req.Header.Set("If-None-Match", string(etag))
+	}
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		if cached, cerr := h.readCache(docPath); cerr == nil {
+			return cached, nil
+		}
+		return nil, fmt.Errorf("loading @context %q: %w", iri, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified {
+		cached, cerr := h.readCache(docPath)
+		if cerr != nil {
+			return nil, fmt.Errorf("loading @context %q: cached copy missing after 304: %w", iri, cerr)
+		}
+		return cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("loading @context %q: unexpected status %s", iri, resp.Status)
+	}
+	var doc JSONLD
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("loading @context %q: %w", iri, err)
+	}
+	if h.CacheDir != "" {
+		h.writeCache(docPath, etagPath, doc, resp.Header.Get("ETag"))
+	}
+	return doc, nil
+}
+
+func (h *HTTPContextLoader) cachePaths(iri string) (docPath, etagPath string) {
+	sum := sha256.Sum256([]byte(iri))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(h.CacheDir, key+".json"), filepath.Join(h.CacheDir, key+".etag")
+}
+
+func (h *HTTPContextLoader) readCache(docPath string) (JSONLD, error) {
+	b, err := ioutil.ReadFile(docPath)
+	if err != nil {
+		return nil, err
+	}
+	var doc JSONLD
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (h *HTTPContextLoader) writeCache(docPath, etagPath string, doc JSONLD, etag string) {
+	if err := os.MkdirAll(h.CacheDir, 0o755); err != nil {
+		return
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(docPath, b, 0o644)
+	if etag != "" {
+		_ = ioutil.WriteFile(etagPath, []byte(etag), 0o644)
+	}
+}
@@ -0,0 +1,148 @@
+package rdf
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestNoRemoteLoaderRefusesEveryIRI(t *testing.T) {
+	_, err := NoRemoteLoader{}.Load("https://example.com/context")
+	if err == nil {
+		t.Fatal("expected an error, remote resolution is supposed to be disabled")
+	}
+	if !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("error %q does not say resolution is disabled", err.Error())
+	}
+}
+
+func TestOfflineContextLoaderServesBundledContexts(t *testing.T) {
+	o := NewOfflineContextLoader()
+	doc, err := o.Load("https://www.w3.org/ns/activitystreams")
+	if err != nil {
+		t.Fatalf("loading bundled context returned error: %v", err)
+	}
+	if _, ok := doc["@context"]; !ok {
+		t.Errorf("bundled document missing @context: %v", doc)
+	}
+}
+
+func TestOfflineContextLoaderSeedOverridesAndAdds(t *testing.T) {
+	o := NewOfflineContextLoader()
+	seeded := JSONLD{"@context": "https://example.com/b"}
+	o.Seed("https://www.w3.org/ns/activitystreams", seeded)
+	o.Seed("https://example.com/custom", seeded)
+
+	for _, iri := range []string{"https://www.w3.org/ns/activitystreams", "https://example.com/custom"} {
+		doc, err := o.Load(iri)
+		if err != nil {
+			t.Fatalf("loading %q returned error: %v", iri, err)
+		}
+		if doc["@context"] != "https://example.com/b" {
+			t.Errorf("loading %q did not return the seeded document: %v", iri, doc)
+		}
+	}
+}
+
+func TestOfflineContextLoaderRejectsUnknownIRI(t *testing.T) {
+	o := NewOfflineContextLoader()
+	if _, err := o.Load("https://example.com/nowhere"); err == nil {
+		t.Fatal("expected an error for an IRI with no bundled or seeded copy")
+	}
+}
+
+func TestHTTPContextLoaderFetchesAndCaches(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"@context": {"@vocab": "https://example.com/ns#"}}`))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "rdf-context-cache")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := NewHTTPContextLoader(dir, srv.Client())
+	doc, err := h.Load(srv.URL)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if _, ok := doc["@context"]; !ok {
+		t.Errorf("decoded document missing @context: %v", doc)
+	}
+	if hits != 1 {
+		t.Fatalf("expected exactly 1 request to the server, got %d", hits)
+	}
+
+	docPath, etagPath := h.cachePaths(srv.URL)
+	if _, err := os.Stat(docPath); err != nil {
+		t.Errorf("expected cached document at %s: %v", docPath, err)
+	}
+	if _, err := os.Stat(etagPath); err != nil {
+		t.Errorf("expected cached ETag at %s: %v", etagPath, err)
+	}
+}
+
+func TestHTTPContextLoaderUsesCacheOn304(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"@context": {"@vocab": "https://example.com/ns#"}}`))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "rdf-context-cache")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	h := NewHTTPContextLoader(dir, srv.Client())
+	if _, err := h.Load(srv.URL); err != nil {
+		t.Fatalf("first Load returned error: %v", err)
+	}
+	doc, err := h.Load(srv.URL)
+	if err != nil {
+		t.Fatalf("second Load (expected 304) returned error: %v", err)
+	}
+	if _, ok := doc["@context"]; !ok {
+		t.Errorf("document served from cache after 304 missing @context: %v", doc)
+	}
+}
+
+func TestHTTPContextLoaderFallsBackToCacheOnNetworkFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rdf-context-cache")
+	if err != nil {
+		t.Fatalf("TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A server that has already been closed refuses every connection.
+	srv := httptest.NewServer(http.NotFoundHandler())
+	unreachable := srv.URL
+	srv.Close()
+
+	h := NewHTTPContextLoader(dir, http.DefaultClient)
+	docPath, _ := h.cachePaths(unreachable)
+	if err := ioutil.WriteFile(docPath, []byte(`{"@context": "https://example.com/ns"}`), 0o644); err != nil {
+		t.Fatalf("seeding cache file: %v", err)
+	}
+
+	doc, err := h.Load(unreachable)
+	if err != nil {
+		t.Fatalf("expected cached fallback, got error: %v", err)
+	}
+	if doc["@context"] != "https://example.com/ns" {
+		t.Errorf("expected the cached document, got: %v", doc)
+	}
+}
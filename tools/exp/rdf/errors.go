@@ -0,0 +1,50 @@
+package rdf
+
+import "strings"
+
+// ParseOptions controls optional ParseVocabulary behavior beyond the
+// zero-value default of stopping at the first error encountered.
+type ParseOptions struct {
+	// ContinueOnError makes ParseVocabulary keep parsing after an error
+	// instead of stopping at the first one. The returned *ParsedVocabulary
+	// contains every term that was successfully parsed despite the errors,
+	// and the returned error is a *MultiError describing all of them.
+	ContinueOnError bool
+}
+
+// MultiError aggregates every error encountered while parsing in
+// ContinueOnError mode, so a single lint pass can report all of them
+// instead of only the first.
+type MultiError struct {
+	Errors []error
+}
+
+// Add appends err to the MultiError. A nil err is a no-op.
+func (m *MultiError) Add(err error) {
+	if err != nil {
+		m.Errors = append(m.Errors, err)
+	}
+}
+
+// HasErrors reports whether any error has been added.
+func (m *MultiError) HasErrors() bool {
+	return len(m.Errors) > 0
+}
+
+// ErrorOrNil returns m if it has accumulated any errors, or nil otherwise --
+// useful for returning `ctx.Errors.ErrorOrNil()` from a function that may or
+// may not have hit a problem.
+func (m *MultiError) ErrorOrNil() error {
+	if !m.HasErrors() {
+		return nil
+	}
+	return m
+}
+
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.Errors))
+	for i, e := range m.Errors {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
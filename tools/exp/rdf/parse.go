@@ -1,13 +1,20 @@
 package rdf
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 const (
 	JSON_LD_CONTEXT = "@context"
 	JSON_LD_TYPE    = "@type"
 	JSON_LD_TYPE_AS = "type"
+	JSON_LD_VOCAB   = "@vocab"
+	JSON_LD_BASE    = "@base"
 )
 
 // JSONLD is an alias for the generic map of keys to interfaces, presumably
@@ -32,6 +39,86 @@ type ParsingContext struct {
 	//
 	// Do not touch, instead use the accessor methods.
 	OnlyApplyThisNode RDFNode
+	// ActiveTerms tracks the JSON-LD 1.1 term definitions currently in
+	// scope, keyed by term name, so an RDFNode's Apply can consult the
+	// @id/@type/@container coercion declared for the key it is applying.
+	//
+	// Do not touch, instead use PushTerm/PopTerm/TermFor.
+	ActiveTerms map[string]*TermDefinition
+	// Path is the JSON-pointer path, from the document root, of the key
+	// currently being parsed -- e.g. ["@context", "1", "actor", "@type"] --
+	// used to describe where a parsing error occurred.
+	//
+	// Do not touch, instead use PushPath/PopPath/JSONPointer.
+	Path []string
+	// Options holds the ParseOptions this parse was started with.
+	Options ParseOptions
+	// Errors accumulates every error encountered while Options.ContinueOnError
+	// is set, instead of apply stopping at the first one.
+	Errors *MultiError
+	// Visiting tracks the alias names currently being expanded by
+	// parseJSONLDContext, so an alias that is declared again by a remote
+	// @context pulled in while expanding it is rejected with a clear cycle
+	// error instead of recursing indefinitely. A term definition whose @id
+	// names its own term, like {"actor": {"@id": "actor"}}, is rejected
+	// earlier, by parseTermDefinition.
+	//
+	// Do not touch, instead use EnterAlias/ExitAlias.
+	Visiting map[string]bool
+}
+
+// PushPath extends Path with a new path segment.
+func (p *ParsingContext) PushPath(segment string) {
+	p.Path = append(p.Path, segment)
+}
+
+// PopPath removes the most recently pushed path segment.
+func (p *ParsingContext) PopPath() {
+	p.Path = p.Path[:len(p.Path)-1]
+}
+
+// JSONPointer renders Path as a JSON pointer, e.g. "/@context/1/actor/@type".
+func (p *ParsingContext) JSONPointer() string {
+	return "/" + strings.Join(p.Path, "/")
+}
+
+// PushTerm brings a JSON-LD 1.1 term definition into scope for name.
+func (p *ParsingContext) PushTerm(name string, td *TermDefinition) {
+	if p.ActiveTerms == nil {
+		p.ActiveTerms = make(map[string]*TermDefinition, 1)
+	}
+	p.ActiveTerms[name] = td
+}
+
+// PopTerm removes name's term definition from scope.
+func (p *ParsingContext) PopTerm(name string) {
+	delete(p.ActiveTerms, name)
+}
+
+// TermFor returns the active term definition for name, or nil if name has
+// no expanded term definition in scope.
+func (p *ParsingContext) TermFor(name string) *TermDefinition {
+	return p.ActiveTerms[name]
+}
+
+// EnterAlias records that name is currently being expanded -- resolving a
+// string alias or a term definition's @id -- returning an error describing
+// the cycle if name is already being expanded further up the same chain.
+func (p *ParsingContext) EnterAlias(name string) error {
+	if p.Visiting == nil {
+		p.Visiting = make(map[string]bool, 1)
+	}
+	if p.Visiting[name] {
+		return fmt.Errorf("cyclic @context reference detected: %q refers back to itself", name)
+	}
+	p.Visiting[name] = true
+	return nil
+}
+
+// ExitAlias removes name from the visiting chain once its expansion has
+// finished, successfully or not.
+func (p *ParsingContext) ExitAlias(name string) {
+	delete(p.Visiting, name)
 }
 
 func (p *ParsingContext) SetOnlyApplyThisNode(n RDFNode) {
@@ -118,106 +205,290 @@ type RDFNode interface {
 }
 
 // ParseVocabulary parses the specified input as an ActivityStreams context that
-// specifies a Core, Extended, or Extension vocabulary.
-func ParseVocabulary(registry *RDFRegistry, input JSONLD) (vocabulary *ParsedVocabulary, err error) {
+// specifies a Core, Extended, or Extension vocabulary. Keys at every level
+// are visited in sorted order, so parsing the same input always produces
+// the same ParsedVocabulary -- important for code generators consuming it.
+//
+// With the zero-value ParseOptions, parsing stops at the first error. With
+// opts.ContinueOnError set, parsing keeps going and the returned
+// *ParsedVocabulary contains every term that was successfully parsed; the
+// returned error is a *MultiError describing every problem found, each one
+// prefixed with the JSON pointer of the node that caused it.
+func ParseVocabulary(registry *RDFRegistry, input JSONLD, opts ParseOptions) (vocabulary *ParsedVocabulary, err error) {
+	vocabulary = &ParsedVocabulary{}
+	ctx := &ParsingContext{
+		Result:  vocabulary,
+		Options: opts,
+		Errors:  &MultiError{},
+	}
 	var nodes []RDFNode
-	nodes, err = parseJSONLDContext(registry, input)
-	if err != nil {
+	nodes, err = parseJSONLDContext(registry, ctx, input, opts)
+	if err != nil && !opts.ContinueOnError {
 		return
 	}
-	vocabulary = &ParsedVocabulary{}
-	ctx := &ParsingContext{
-		Result: vocabulary,
+	if me, ok := err.(*MultiError); ok {
+		ctx.Errors = me
+	} else if err != nil {
+		ctx.Errors.Add(err)
 	}
 	// Prepend well-known JSON LD parsing nodes. Order matters, so that the
 	// parser can understand things like types so that other nodes do not
 	// hijack processing.
 	nodes = append(jsonLDNodes(registry), nodes...)
-	err = apply(nodes, input, ctx)
+	if aerr := apply(nodes, input, ctx); aerr != nil && !opts.ContinueOnError {
+		err = aerr
+		return
+	}
+	err = ctx.Errors.ErrorOrNil()
 	return
 }
 
+// ParseVocabularyReader is ParseVocabulary for callers that have a
+// potentially multi-megabyte vocabulary document on disk or the wire: it
+// decodes the JSON-LD document directly from r via encoding/json's
+// Decoder, instead of requiring the caller to first buffer the whole
+// document into a JSONLD value themselves.
+func ParseVocabularyReader(registry *RDFRegistry, r io.Reader, opts ParseOptions) (*ParsedVocabulary, error) {
+	var input JSONLD
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(&input); err != nil {
+		return nil, fmt.Errorf("decoding vocabulary: %w", err)
+	}
+	return ParseVocabulary(registry, input, opts)
+}
+
 // apply takes a specification input to populate the ParsingContext, based on
-// the capabilities of the RDFNodes created from ontologies.
+// the capabilities of the RDFNodes created from ontologies. Processing uses
+// an explicit work-stack of applyFrames instead of Go recursion, so that a
+// deeply nested extension vocabulary cannot blow the goroutine stack.
+//
+// When ctx.Options.ContinueOnError is set, a frame that errors is abandoned
+// -- its error is recorded on ctx.Errors, prefixed with the JSON pointer of
+// the node it happened at, and the path is unwound back to where that frame
+// was pushed -- instead of aborting the whole parse. The frame that pushed
+// it still owns its own key segment and Enter/clearFn state, so its resume
+// runs as part of the unwind rather than being discarded, and a later
+// sibling key reports its own JSON pointer correctly instead of one still
+// carrying the abandoned frame's path.
 func apply(nodes []RDFNode, input JSONLD, ctx *ParsingContext) error {
-	// Hijacked processing: Process the rest of the data in this single
-	// node.
-	if ctx.OnlyApplyThisNode != nil {
-		if applied, err := ctx.OnlyApplyThisNode.Apply("", input, ctx); !applied {
-			return fmt.Errorf("applying requested node failed")
-		} else {
+	stack := []*applyFrame{newApplyFrame(nodes, input)}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		done, child, err := top.step(ctx)
+		if err != nil {
+			if ctx.Options.ContinueOnError {
+				ctx.Errors.Add(fmt.Errorf("%s: %w", ctx.JSONPointer(), err))
+				ctx.Path = ctx.Path[:top.pathBase]
+				stack = stack[:len(stack)-1]
+				// top's own key segment (and the Enter/clearFn state that
+				// goes with it) is still the new top-of-stack frame's
+				// unfinished business: that frame's resume is what pops it,
+				// so run it now instead of discarding it, or its key
+				// segment and OnlyApplied state leak into the rest of that
+				// level's processing.
+				if len(stack) > 0 {
+					if resume := stack[len(stack)-1].resume; resume != nil {
+						stack[len(stack)-1].resume = nil
+						if rerr := resume(ctx); rerr != nil {
+							ctx.Errors.Add(fmt.Errorf("%s: %w", ctx.JSONPointer(), rerr))
+						}
+					}
+				}
+				continue
+			}
 			return err
 		}
-		return nil
-	}
-	// Special processing: '@type' or 'type' if they are present
-	if v, ok := input[JSON_LD_TYPE]; ok {
-		if err := doApply(nodes, JSON_LD_TYPE, v, ctx); err != nil {
-			return err
+		if child != nil {
+			child.pathBase = len(ctx.Path)
+			stack = append(stack, child)
+			continue
 		}
-	} else if v, ok := input[JSON_LD_TYPE_AS]; ok {
-		if err := doApply(nodes, JSON_LD_TYPE_AS, v, ctx); err != nil {
-			return err
+		if done {
+			stack = stack[:len(stack)-1]
 		}
 	}
-	// Normal recursive processing
-	for k, v := range input {
-		// Skip things we have already processed: context and type
-		if k == JSON_LD_CONTEXT {
-			continue
-		} else if k == JSON_LD_TYPE {
-			continue
-		} else if k == JSON_LD_TYPE_AS {
+	return nil
+}
+
+// applyFrame tracks one level of apply's explicit work-stack: the nodes
+// available at this level, the keys left to process (in sorted order, '@type'
+// or 'type' first if present, matching the original recursive semantics),
+// and -- while a single key's map or array value is being recursed into --
+// enough state to resume processing that key once the pushed child frame
+// finishes.
+type applyFrame struct {
+	nodes []RDFNode
+	input JSONLD
+	keys  []string
+	pos   int
+
+	// arr, when non-nil, holds the remaining elements of an array-valued
+	// key still being processed, with arrNodes/arrClear the Enter/Apply/Exit
+	// nodes and cleanup selected once for that key, same as the original
+	// doApply computed them once per key rather than once per element.
+	arr      []interface{}
+	arrPos   int
+	arrKey   string
+	arrNodes []RDFNode
+	arrClear func()
+
+	// resume, when set, is invoked the next time step is called, to run the
+	// Exit call (and any cleanup) that corresponds to a child frame this
+	// frame just pushed, before moving on to the rest of its own work.
+	resume func(ctx *ParsingContext) error
+
+	// pathBase is ctx.Path's length at the moment this frame was pushed
+	// onto apply's work-stack, so a ContinueOnError abort can unwind
+	// ctx.Path back to exactly where it stood before this frame ran.
+	pathBase int
+}
+
+// newApplyFrame builds the frame for a single JSONLD map, precomputing the
+// order keys will be visited in: '@type'/'type' first if present (mirroring
+// the dedicated pre-pass the original recursive apply did), then the
+// remaining keys sorted, skipping '@context' and the type keys.
+func newApplyFrame(nodes []RDFNode, input JSONLD) *applyFrame {
+	var keys []string
+	if _, ok := input[JSON_LD_TYPE]; ok {
+		keys = append(keys, JSON_LD_TYPE)
+	} else if _, ok := input[JSON_LD_TYPE_AS]; ok {
+		keys = append(keys, JSON_LD_TYPE_AS)
+	}
+	for _, k := range sortedJSONLDKeys(input) {
+		if k == JSON_LD_CONTEXT || k == JSON_LD_TYPE || k == JSON_LD_TYPE_AS {
 			continue
 		}
-		if err := doApply(nodes, k, v, ctx); err != nil {
-			return err
+		keys = append(keys, k)
+	}
+	return &applyFrame{nodes: nodes, input: input, keys: keys}
+}
+
+// step advances f by one unit of work. It returns done=true once f has no
+// work left, or a non-nil child frame that must be fully processed (pushed
+// on top of the work-stack) before f can resume.
+func (f *applyFrame) step(ctx *ParsingContext) (done bool, child *applyFrame, err error) {
+	if f.resume != nil {
+		resume := f.resume
+		f.resume = nil
+		err = resume(ctx)
+		return
+	}
+	// Hijacked processing: process the rest of the data in this single node.
+	if ctx.OnlyApplyThisNode != nil {
+		applied, aerr := ctx.OnlyApplyThisNode.Apply("", f.input, ctx)
+		if !applied {
+			err = fmt.Errorf("applying requested node failed")
+		} else {
+			err = aerr
 		}
+		done = true
+		return
 	}
-	return nil
+	if f.arr != nil {
+		return f.stepArray(ctx)
+	}
+	if f.pos >= len(f.keys) {
+		done = true
+		return
+	}
+	k := f.keys[f.pos]
+	f.pos++
+	return f.stepKey(ctx, k, f.input[k])
 }
 
-// doApply actually does the application logic for the apply function.
-func doApply(nodes []RDFNode,
-	k string, v interface{},
-	ctx *ParsingContext) error {
-	// Hijacked processing: Only use the ParsingContext's node to
-	// handle all elements.
-	recurNodes := nodes
-	enterApplyExitNodes, clearFn := ctx.GetNextNodes(nodes)
-	defer clearFn()
-	// Normal recursive processing
+// stepKey begins processing key k's value v: entering the node selected for
+// k, then either pushing a child frame to recurse into a nested map,
+// beginning array iteration, or applying a scalar value directly.
+func (f *applyFrame) stepKey(ctx *ParsingContext, k string, v interface{}) (done bool, child *applyFrame, err error) {
+	enterApplyExitNodes, clearFn := ctx.GetNextNodes(f.nodes)
+	recurNodes := f.nodes
+
 	if mapValue, ok := v.(map[string]interface{}); ok {
-		if err := enterFirstNode(enterApplyExitNodes, k, ctx); err != nil {
-			return err
-		} else if err = apply(recurNodes, mapValue, ctx); err != nil {
-			return err
-		} else if err = exitFirstNode(enterApplyExitNodes, k, ctx); err != nil {
+		ctx.PushPath(k)
+		if err = enterFirstNode(enterApplyExitNodes, k, ctx); err != nil {
+			// Leave k's path segment in place: apply's caller records the
+			// JSON pointer for this error before unwinding ctx.Path, so the
+			// pointer must still include the key that failed.
+			clearFn()
+			return
+		}
+		child = newApplyFrame(recurNodes, mapValue)
+		f.resume = func(ctx *ParsingContext) error {
+			err := exitFirstNode(enterApplyExitNodes, k, ctx)
+			ctx.PopPath()
+			clearFn()
 			return err
 		}
-	} else if arrValue, ok := v.([]interface{}); ok {
-		for _, val := range arrValue {
-			// First, enter for this key
-			if err := enterFirstNode(enterApplyExitNodes, k, ctx); err != nil {
-				return err
-			}
-			// Recur or handle the value as necessary.
-			if mapValue, ok := val.(map[string]interface{}); ok {
-				if err := apply(recurNodes, mapValue, ctx); err != nil {
-					return err
-				}
-			} else if err := applyFirstNode(enterApplyExitNodes, k, val, ctx); err != nil {
-				return err
-			}
-			// Finally, exit for this key
-			if err := exitFirstNode(enterApplyExitNodes, k, ctx); err != nil {
-				return err
-			}
+		return
+	}
+	if arrValue, ok := v.([]interface{}); ok {
+		f.arr = arrValue
+		f.arrPos = 0
+		f.arrKey = k
+		f.arrNodes = enterApplyExitNodes
+		f.arrClear = clearFn
+		ctx.PushPath(k)
+		return f.stepArray(ctx)
+	}
+	ctx.PushPath(k)
+	if err = applyFirstNode(enterApplyExitNodes, k, v, ctx); err != nil {
+		// Leave k's path segment in place; see the map-value branch above.
+		clearFn()
+		return
+	}
+	ctx.PopPath()
+	clearFn()
+	return
+}
+
+// stepArray processes the next element of an in-progress array value,
+// mirroring the original doApply's per-element Enter/recurse-or-Apply/Exit
+// sequence, and runs the array's one-time cleanup once every element has
+// been handled.
+func (f *applyFrame) stepArray(ctx *ParsingContext) (done bool, child *applyFrame, err error) {
+	if f.arrPos >= len(f.arr) {
+		f.arrClear()
+		f.arr = nil
+		ctx.PopPath()
+		return
+	}
+	idx := f.arrPos
+	val := f.arr[idx]
+	f.arrPos++
+	ctx.PushPath(strconv.Itoa(idx))
+	if err = enterFirstNode(f.arrNodes, f.arrKey, ctx); err != nil {
+		// Leave the index segment in place; see stepKey's map-value branch.
+		return
+	}
+	if mapValue, ok := val.(map[string]interface{}); ok {
+		nodes, key := f.arrNodes, f.arrKey
+		child = newApplyFrame(f.nodes, mapValue)
+		f.resume = func(ctx *ParsingContext) error {
+			err := exitFirstNode(nodes, key, ctx)
+			ctx.PopPath()
+			return err
 		}
-	} else if err := applyFirstNode(enterApplyExitNodes, k, v, ctx); err != nil {
-		return err
+		return
 	}
-	return nil
+	if err = applyFirstNode(f.arrNodes, f.arrKey, val, ctx); err != nil {
+		// Leave the index segment in place; see stepKey's map-value branch.
+		return
+	}
+	err = exitFirstNode(f.arrNodes, f.arrKey, ctx)
+	ctx.PopPath()
+	return
+}
+
+// sortedJSONLDKeys returns input's keys in sorted order, so map traversal
+// throughout this package is deterministic.
+func sortedJSONLDKeys(input JSONLD) []string {
+	keys := make([]string, 0, len(input))
+	for k := range input {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // enterFirstNode will Enter the first RDFNode that returns true or an error.
@@ -258,80 +529,114 @@ func applyFirstNode(nodes []RDFNode, key string, value interface{}, ctx *Parsing
 
 // parseJSONLDContext implements a super basic JSON-LD @context parsing
 // algorithm in order to build a set of nodes which will be able to parse the
-// rest of the document.
-func parseJSONLDContext(registry *RDFRegistry, input JSONLD) (nodes []RDFNode, err error) {
+// rest of the document. IRIs that are not already registered with registry
+// are resolved through its ContextLoader, so an extension vocabulary's
+// @context does not need to be pre-registered to be understood. ctx's
+// Visiting set guards every alias/term expansion this makes, including ones
+// that recurse through a remote @context's own entries, against a cycle --
+// see ParsingContext.EnterAlias.
+//
+// With opts.ContinueOnError, a bad entry does not abort the whole @context:
+// it is recorded, prefixed with its JSON pointer, on the returned
+// *MultiError, and the remaining entries are still parsed.
+func parseJSONLDContext(registry *RDFRegistry, ctx *ParsingContext, input JSONLD, opts ParseOptions) (nodes []RDFNode, err error) {
 	i, ok := input[JSON_LD_CONTEXT]
 	if !ok {
 		err = fmt.Errorf("no @context in input")
 		return
 	}
+	errs := &MultiError{}
+	// fail records e against path. It returns false (meaning "stop now") if
+	// opts.ContinueOnError is unset, in which case err is also set so the
+	// caller can return immediately.
+	fail := func(path string, e error) bool {
+		wrapped := fmt.Errorf("%s: %w", path, e)
+		if opts.ContinueOnError {
+			errs.Add(wrapped)
+			return true
+		}
+		err = wrapped
+		return false
+	}
 	if inArray, ok := i.([]interface{}); ok {
 		// @context is an array
-		for _, iVal := range inArray {
+		for idx, iVal := range inArray {
 			if valMap, ok := iVal.(map[string]interface{}); ok {
 				// Element is a JSON Object (dictionary)
-				for alias, val := range valMap {
-					if s, ok := val.(string); ok {
-						var n []RDFNode
-						n, err = registry.getAliased(alias, s)
-						if err != nil {
+				for _, alias := range sortedJSONLDKeys(valMap) {
+					n, e := handleContextEntry(registry, ctx, alias, valMap[alias])
+					if e != nil {
+						if !fail(fmt.Sprintf("/@context/%d/%s", idx, alias), e) {
 							return
 						}
-						nodes = append(nodes, n...)
-					} else if aliasedMap, ok := val.(map[string]interface{}); ok {
-						var n []RDFNode
-						n, err = registry.getAliasedObject(alias, aliasedMap)
-						if err != nil {
-							return
-						}
-						nodes = append(nodes, n...)
-					} else {
-						err = fmt.Errorf("@context value in dict in array is neither a dict nor a string")
-						return
+						continue
 					}
+					nodes = append(nodes, n...)
 				}
 			} else if s, ok := iVal.(string); ok {
 				// Element is a single value
-				var n []RDFNode
-				n, err = registry.getFor(s)
-				if err != nil {
-					return
+				n, e := registry.getFor(ctx, s)
+				if e != nil {
+					if !fail(fmt.Sprintf("/@context/%d", idx), e) {
+						return
+					}
+					continue
 				}
 				nodes = append(nodes, n...)
-			} else {
-				err = fmt.Errorf("@context value in array is neither a dict nor a string")
+			} else if !fail(fmt.Sprintf("/@context/%d", idx), fmt.Errorf("@context value in array is neither a dict nor a string")) {
 				return
 			}
 		}
 	} else if inMap, ok := i.(map[string]interface{}); ok {
 		// @context is a JSON object (dictionary)
-		for alias, iVal := range inMap {
-			if s, ok := iVal.(string); ok {
-				var n []RDFNode
-				n, err = registry.getAliased(alias, s)
-				if err != nil {
+		for _, alias := range sortedJSONLDKeys(inMap) {
+			n, e := handleContextEntry(registry, ctx, alias, inMap[alias])
+			if e != nil {
+				if !fail(fmt.Sprintf("/@context/%s", alias), e) {
 					return
 				}
-				nodes = append(nodes, n...)
-			} else if aliasedMap, ok := iVal.(map[string]interface{}); ok {
-				var n []RDFNode
-				n, err = registry.getAliasedObject(alias, aliasedMap)
-				if err != nil {
-					return
-				}
-				nodes = append(nodes, n...)
-			} else {
-				err = fmt.Errorf("@context value in dict is neither a dict nor a string")
-				return
+				continue
 			}
+			nodes = append(nodes, n...)
 		}
 	} else {
 		// @context is a single value
 		s, ok := i.(string)
 		if !ok {
-			err = fmt.Errorf("single @context value is not a string")
+			err = fmt.Errorf("/@context: single @context value is not a string")
+			return
 		}
-		return registry.getFor(s)
+		nodes, err = registry.getFor(ctx, s)
+		if err != nil {
+			err = fmt.Errorf("/@context: %w", err)
+		}
+		return
+	}
+	if opts.ContinueOnError {
+		err = errs.ErrorOrNil()
 	}
 	return
 }
+
+// handleContextEntry resolves a single "alias: value" entry of an @context
+// object. "@vocab" and "@base" set the registry's default vocabulary IRI
+// and base IRI rather than being treated as term aliases; every other key
+// is either a simple string alias or a JSON-LD 1.1 expanded term
+// definition.
+func handleContextEntry(registry *RDFRegistry, ctx *ParsingContext, alias string, val interface{}) ([]RDFNode, error) {
+	if s, ok := val.(string); ok {
+		switch alias {
+		case JSON_LD_VOCAB:
+			registry.SetVocabIRI(s)
+			return nil, nil
+		case JSON_LD_BASE:
+			registry.SetBaseIRI(s)
+			return nil, nil
+		default:
+			return registry.getAliased(ctx, alias, s)
+		}
+	} else if aliasedMap, ok := val.(map[string]interface{}); ok {
+		return registry.getAliasedObject(ctx, alias, aliasedMap)
+	}
+	return nil, fmt.Errorf("@context value for %q is neither a dict nor a string", alias)
+}
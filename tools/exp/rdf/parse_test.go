@@ -0,0 +1,91 @@
+package rdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// stubNode is a minimal RDFNode that applies every key it is given, failing
+// Apply when the value is the sentinel string "boom" -- just enough
+// behavior to drive apply()'s explicit work-stack through nested maps
+// without needing a real vocabulary registered.
+type stubNode struct{}
+
+func (stubNode) Enter(key string, ctx *ParsingContext) (bool, error) { return true, nil }
+func (stubNode) Exit(key string, ctx *ParsingContext) (bool, error)  { return true, nil }
+func (stubNode) Apply(key string, value interface{}, ctx *ParsingContext) (bool, error) {
+	if s, ok := value.(string); ok && s == "boom" {
+		return true, fmt.Errorf("stub failure for key %q", key)
+	}
+	return true, nil
+}
+
+func newStubContext() *ParsingContext {
+	return &ParsingContext{
+		Result:  &ParsedVocabulary{},
+		Options: ParseOptions{ContinueOnError: true},
+		Errors:  &MultiError{},
+	}
+}
+
+func TestApplyContinueOnErrorUnwindsSiblingPath(t *testing.T) {
+	input := JSONLD{
+		"actor": map[string]interface{}{"name": "boom"},
+		"name":  map[string]interface{}{"inner": "boom"},
+	}
+	ctx := newStubContext()
+	if err := apply([]RDFNode{stubNode{}}, input, ctx); err != nil {
+		t.Fatalf("apply returned error despite ContinueOnError: %v", err)
+	}
+	if len(ctx.Errors.Errors) != 2 {
+		t.Fatalf("want 2 aggregated errors, got %d: %v", len(ctx.Errors.Errors), ctx.Errors.Errors)
+	}
+	if got := ctx.Errors.Errors[0].Error(); !strings.HasPrefix(got, "/actor/name:") {
+		t.Errorf("first error pointer = %q, want prefix %q", got, "/actor/name:")
+	}
+	if got := ctx.Errors.Errors[1].Error(); !strings.HasPrefix(got, "/name/inner:") {
+		t.Errorf("second error pointer = %q, want prefix %q -- a leaked /actor prefix means the parent frame's own key segment was never unwound", got, "/name/inner:")
+	}
+	if len(ctx.Path) != 0 {
+		t.Errorf("ctx.Path leaked after both frames unwound: %v", ctx.Path)
+	}
+}
+
+func TestApplyScalarLeafErrorIncludesItsOwnKeyInPointer(t *testing.T) {
+	input := JSONLD{"leaf": "boom"}
+	ctx := newStubContext()
+	if err := apply([]RDFNode{stubNode{}}, input, ctx); err != nil {
+		t.Fatalf("apply returned error despite ContinueOnError: %v", err)
+	}
+	if len(ctx.Errors.Errors) != 1 {
+		t.Fatalf("want 1 aggregated error, got %d: %v", len(ctx.Errors.Errors), ctx.Errors.Errors)
+	}
+	if got := ctx.Errors.Errors[0].Error(); !strings.HasPrefix(got, "/leaf:") {
+		t.Errorf("error pointer = %q, want prefix %q", got, "/leaf:")
+	}
+}
+
+// TestParseVocabularyReportsFullNestedJSONPointer exercises the JSON pointer
+// machinery end to end through ParseVocabulary, not just apply() directly,
+// confirming a deeply nested error is reported with every key down to the
+// leaf that actually failed -- the parser-rework's headline deliverable.
+func TestParseVocabularyReportsFullNestedJSONPointer(t *testing.T) {
+	registry := NewRDFRegistry()
+	if err := registry.RegisterVocabulary("https://example.com/test", []RDFNode{stubNode{}}); err != nil {
+		t.Fatalf("RegisterVocabulary: %v", err)
+	}
+	input := JSONLD{
+		"@context": map[string]interface{}{"test": "https://example.com/test"},
+		"actor": map[string]interface{}{
+			"@type": map[string]interface{}{"nested": "boom"},
+		},
+	}
+	_, err := ParseVocabulary(registry, input, ParseOptions{ContinueOnError: true})
+	if err == nil {
+		t.Fatal("expected an error for the nested boom leaf")
+	}
+	if !strings.Contains(err.Error(), "/actor/@type/nested:") {
+		t.Errorf("error %q does not contain the full nested pointer %q", err.Error(), "/actor/@type/nested:")
+	}
+}
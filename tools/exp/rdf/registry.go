@@ -0,0 +1,154 @@
+package rdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RDFRegistry tracks the vocabularies known to the parser, along with the
+// aliases declared for them in @context entries, and vends the RDFNodes
+// that know how to interpret each one.
+type RDFRegistry struct {
+	alias  map[string]string
+	spec   map[string][]RDFNode
+	loader ContextLoader
+	vocab  string
+	base   string
+
+	// resolving is the stack of vocabulary/context names currently being
+	// resolved via resolveRemote, used to detect and reject a @context (or
+	// a term definition's @id) that cyclically refers back to one of its
+	// own ancestors.
+	resolving []string
+}
+
+// NewRDFRegistry creates an empty RDFRegistry. Remote @context resolution
+// is disabled by default; call SetContextLoader to enable it.
+func NewRDFRegistry() *RDFRegistry {
+	return &RDFRegistry{
+		alias:  make(map[string]string),
+		spec:   make(map[string][]RDFNode),
+		loader: NoRemoteLoader{},
+	}
+}
+
+// SetContextLoader installs the ContextLoader used to resolve @context IRIs
+// that have not been pre-registered with this RDFRegistry.
+func (r *RDFRegistry) SetContextLoader(loader ContextLoader) {
+	r.loader = loader
+}
+
+// SetVocabIRI records the default vocabulary IRI declared by an @context's
+// "@vocab" entry. The registry only stores it: nothing in this package
+// reads it, so bare terms are not expanded against it.
+func (r *RDFRegistry) SetVocabIRI(iri string) {
+	r.vocab = iri
+}
+
+// SetBaseIRI records the base IRI declared by an @context's "@base" entry.
+// The registry only stores it: nothing in this package reads it, so
+// relative IRIs are not resolved against it.
+func (r *RDFRegistry) SetBaseIRI(iri string) {
+	r.base = iri
+}
+
+// RegisterVocabulary associates a vocabulary name with the RDFNodes capable
+// of parsing its members.
+func (r *RDFRegistry) RegisterVocabulary(name string, nodes []RDFNode) error {
+	if _, ok := r.spec[name]; ok {
+		return fmt.Errorf("vocabulary %q is already registered", name)
+	}
+	r.spec[name] = nodes
+	return nil
+}
+
+// getFor obtains the RDFNodes responsible for parsing the named vocabulary,
+// falling back to the registry's ContextLoader to resolve and materialize
+// it remotely if it has not been pre-registered.
+func (r *RDFRegistry) getFor(ctx *ParsingContext, name string) ([]RDFNode, error) {
+	if n, ok := r.spec[name]; ok {
+		return n, nil
+	}
+	return r.resolveRemote(ctx, name)
+}
+
+// getAliased resolves an alias declared in an @context entry to the
+// underlying vocabulary's RDFNodes. ctx.EnterAlias guards the resolution
+// against a remote @context it pulls in declaring alias again, instead of
+// recursing indefinitely.
+func (r *RDFRegistry) getAliased(ctx *ParsingContext, alias, name string) ([]RDFNode, error) {
+	if err := ctx.EnterAlias(alias); err != nil {
+		return nil, err
+	}
+	defer ctx.ExitAlias(alias)
+	nodes, err := r.getFor(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	r.alias[alias] = name
+	return nodes, nil
+}
+
+// getAliasedObject resolves a JSON-LD 1.1 expanded term definition, such as
+// {"actor": {"@id": "as:actor", "@type": "@id"}}, into the RDFNodes that
+// parse its backing vocabulary plus a termDefinitionNode that keeps the
+// definition available on the ParsingContext for the term's scope.
+func (r *RDFRegistry) getAliasedObject(ctx *ParsingContext, alias string, value map[string]interface{}) ([]RDFNode, error) {
+	td, err := parseTermDefinition(alias, value)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []RDFNode{&termDefinitionNode{def: td}}
+	if td.ID != "" {
+		// Resolve the backing vocabulary the same way a plain string alias
+		// would, so downstream parsing of this term works identically to a
+		// locally registered alias.
+		aliased, err := r.getAliased(ctx, alias, td.ID)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, aliased...)
+	}
+	return nodes, nil
+}
+
+// resolveRemote fetches iri via the registry's ContextLoader, parses the
+// resulting JSON-LD context, and registers the synthesized RDFNodes under
+// iri so subsequent lookups behave as though iri had been registered
+// locally all along. It shares ctx with the caller, so a remote @context
+// whose own entries declare an alias already being expanded is caught by
+// ctx.Visiting, in addition to the IRI-only resolving stack below.
+func (r *RDFRegistry) resolveRemote(ctx *ParsingContext, iri string) ([]RDFNode, error) {
+	for _, ancestor := range r.resolving {
+		if ancestor == iri {
+			return nil, fmt.Errorf("cyclic @context reference detected: %s -> %s",
+				strings.Join(r.resolving, " -> "), iri)
+		}
+	}
+	r.resolving = append(r.resolving, iri)
+	defer func() { r.resolving = r.resolving[:len(r.resolving)-1] }()
+
+	doc, err := r.loader.Load(iri)
+	if err != nil {
+		return nil, fmt.Errorf("resolving @context %q: %w", iri, err)
+	}
+	// A remote @context is always resolved strictly: the registry has no
+	// ContinueOnError preference of its own, so a broken extension
+	// vocabulary fails the lookup it was resolving for, rather than
+	// silently granting partial nodes.
+	nodes, err := parseJSONLDContext(r, ctx, doc, ParseOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("resolving @context %q: %w", iri, err)
+	}
+	if err := r.RegisterVocabulary(iri, nodes); err != nil {
+		return nil, fmt.Errorf("resolving @context %q: %w", iri, err)
+	}
+	return nodes, nil
+}
+
+// jsonLDNodes returns the well-known RDFNodes needed to process any
+// JSON-LD document -- such as consulting a key's active term definition for
+// @id coercion -- regardless of which vocabularies are in use.
+func jsonLDNodes(registry *RDFRegistry) []RDFNode {
+	return []RDFNode{termCoercionNode{}}
+}
@@ -0,0 +1,52 @@
+package rdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsingContextEnterAliasDetectsCycle(t *testing.T) {
+	ctx := &ParsingContext{}
+	if err := ctx.EnterAlias("actor"); err != nil {
+		t.Fatalf("first EnterAlias returned error: %v", err)
+	}
+	if err := ctx.EnterAlias("actor"); err == nil {
+		t.Fatal("expected a cyclic error when re-entering an alias already being expanded")
+	}
+	ctx.ExitAlias("actor")
+	if err := ctx.EnterAlias("actor"); err != nil {
+		t.Fatalf("re-entering after ExitAlias should succeed, got: %v", err)
+	}
+}
+
+func TestParseVocabularyRejectsSelfRecursiveTermDefinition(t *testing.T) {
+	input := JSONLD{
+		"@context": map[string]interface{}{
+			"actor": map[string]interface{}{"@id": "actor"},
+		},
+	}
+	_, err := ParseVocabulary(NewRDFRegistry(), input, ParseOptions{})
+	if err == nil {
+		t.Fatal("expected an error for a term definition whose @id refers back to its own term")
+	}
+	if !strings.Contains(err.Error(), "cyclic") {
+		t.Errorf("error %q does not describe the cycle", err.Error())
+	}
+}
+
+func TestParseVocabularyRejectsCyclicRemoteContext(t *testing.T) {
+	loader := NewOfflineContextLoader()
+	loader.Seed("https://example.com/a", JSONLD{"@context": "https://example.com/b"})
+	loader.Seed("https://example.com/b", JSONLD{"@context": "https://example.com/a"})
+	registry := NewRDFRegistry()
+	registry.SetContextLoader(loader)
+
+	input := JSONLD{"@context": "https://example.com/a"}
+	_, err := ParseVocabulary(registry, input, ParseOptions{})
+	if err == nil {
+		t.Fatal("expected an error for a remote @context cycle")
+	}
+	if !strings.Contains(err.Error(), "cyclic") {
+		t.Errorf("error %q does not describe the cycle", err.Error())
+	}
+}
@@ -0,0 +1,43 @@
+package rdf
+
+import "sort"
+
+// namespacePrefixes assigns a short Turtle prefix to each of v's References,
+// so that cross-vocabulary VocabularyReference values can be rendered as a
+// `prefix:name` CURIE instead of a full IRI. The vocabulary itself is not in
+// the map: it always uses the "this" prefix that writeTurtlePrefixes emits.
+func namespacePrefixes(v *ParsedVocabulary) map[string]string {
+	prefixes := make(map[string]string, len(v.References)+1)
+	names := make([]string, 0, len(v.References))
+	for name := range v.References {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		prefixes[name] = letterPrefix(i)
+	}
+	return prefixes
+}
+
+// letterPrefix deterministically turns an index into a short alphabetic
+// prefix: 0 -> "a", 1 -> "b", ..., 25 -> "z", 26 -> "aa", and so on.
+func letterPrefix(i int) string {
+	if i < 26 {
+		return string(rune('a' + i))
+	}
+	return letterPrefix(i/26-1) + string(rune('a'+i%26))
+}
+
+// curie renders a VocabularyReference as a `prefix:name` CURIE, resolving
+// cross-vocabulary references via prefixes. A reference with no Vocab is
+// assumed to belong to the vocabulary being serialized, under the "this"
+// prefix.
+func curie(ref VocabularyReference, prefixes map[string]string) string {
+	if ref.Vocab == "" {
+		return "this:" + ref.Name
+	}
+	if p, ok := prefixes[ref.Vocab]; ok {
+		return p + ":" + ref.Name
+	}
+	return ref.Vocab + ":" + ref.Name
+}
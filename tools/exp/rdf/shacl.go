@@ -0,0 +1,83 @@
+package rdf
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteSHACL serializes v as a SHACL shapes graph: one sh:NodeShape per
+// VocabularyType, with an sh:property constraint for each property the
+// type's vocabulary declares a Domain for. Functional maps to
+// `sh:maxCount 1`; Range maps to `sh:class` for object properties or
+// `sh:datatype rdf:langString` for a NaturalLanguageMap property.
+func WriteSHACL(w io.Writer, v *ParsedVocabulary) error {
+	prefixes := namespacePrefixes(v)
+	if err := writeTurtlePrefixes(w, prefixes); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprint(w, "@prefix sh: <http://www.w3.org/ns/shacl#> .\n\n"); err != nil {
+		return err
+	}
+	propsByDomain := propertiesByDomain(v.Vocab.Properties)
+	for _, name := range sortedTypeNames(v.Vocab.Types) {
+		t := v.Vocab.Types[name]
+		if err := writeSHACLShape(w, t, propsByDomain[name], prefixes); err != nil {
+			return fmt.Errorf("writing shacl shape for type %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// propertiesByDomain indexes properties by the name of each VocabularyType
+// in their Domain, so a type's shape can list the properties declared to
+// apply to it.
+func propertiesByDomain(properties map[string]VocabularyProperty) map[string][]VocabularyProperty {
+	byDomain := make(map[string][]VocabularyProperty)
+	for _, name := range sortedPropertyNames(properties) {
+		p := properties[name]
+		for _, d := range p.Domain {
+			if d.Vocab != "" {
+				continue
+			}
+			byDomain[d.Name] = append(byDomain[d.Name], p)
+		}
+	}
+	return byDomain
+}
+
+func writeSHACLShape(w io.Writer, t VocabularyType, props []VocabularyProperty, prefixes map[string]string) error {
+	if _, err := fmt.Fprintf(w, "this:%sShape a sh:NodeShape ;\n\tsh:targetClass this:%s", t.Name, t.Name); err != nil {
+		return err
+	}
+	for _, p := range props {
+		if err := writeSHACLProperty(w, p, prefixes); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprint(w, " .\n\n")
+	return err
+}
+
+func writeSHACLProperty(w io.Writer, p VocabularyProperty, prefixes map[string]string) error {
+	if _, err := fmt.Fprintf(w, " ;\n\tsh:property [\n\t\tsh:path this:%s", p.Name); err != nil {
+		return err
+	}
+	if p.Functional {
+		if _, err := fmt.Fprint(w, " ;\n\t\tsh:maxCount 1"); err != nil {
+			return err
+		}
+	}
+	if p.NaturalLanguageMap {
+		if _, err := fmt.Fprint(w, " ;\n\t\tsh:datatype rdf:langString"); err != nil {
+			return err
+		}
+	} else if len(p.Range) > 0 {
+		for _, r := range p.Range {
+			if _, err := fmt.Fprintf(w, " ;\n\t\tsh:class %s", curie(r, prefixes)); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprint(w, "\n\t]")
+	return err
+}
@@ -0,0 +1,165 @@
+package rdf
+
+import "fmt"
+
+// Container enumerates the JSON-LD 1.1 container coercions this package
+// understands for a term definition's "@container" entry.
+type Container int
+
+const (
+	ContainerNone Container = iota
+	ContainerSet
+	ContainerList
+	ContainerIndex
+	ContainerLanguage
+)
+
+func parseContainer(s string) (Container, error) {
+	switch s {
+	case "@set":
+		return ContainerSet, nil
+	case "@list":
+		return ContainerList, nil
+	case "@index":
+		return ContainerIndex, nil
+	case "@language":
+		return ContainerLanguage, nil
+	default:
+		return ContainerNone, fmt.Errorf("unrecognized @container value %q", s)
+	}
+}
+
+// TermDefinition is the expanded form of a JSON-LD 1.1 @context term, as
+// opposed to the bare string-to-IRI aliasing earlier versions of this
+// package assumed every term was.
+type TermDefinition struct {
+	Term      string
+	ID        string
+	Type      string
+	Container Container
+	Language  string
+}
+
+// reservedContextKeywords are the JSON-LD keywords that may not be used as a
+// term name. Of these, parseTermDefinition accepts only @id, @type,
+// @container and @language as keys within a term definition object.
+var reservedContextKeywords = map[string]bool{
+	"@id": true, "@type": true, "@container": true, "@language": true,
+	"@vocab": true, "@base": true, "@context": true, "@reverse": true,
+	"@index": true, "@list": true, "@set": true, "@graph": true,
+}
+
+// parseTermDefinition expands a JSON-LD 1.1 term definition object such as
+// {"@id": "as:actor", "@type": "@id"} into a TermDefinition. It rejects
+// reserved keywords used as a term name, and any key within the object that
+// is not a keyword this package understands. "@vocab" and "@base" are
+// JSON-LD keywords too, but they only apply at the enclosing @context
+// object's own top level (see handleContextEntry) -- nested inside a term
+// definition object they have no meaning, so they fall into the same
+// unrecognized-keyword rejection as any other key this package doesn't
+// understand here.
+func parseTermDefinition(term string, value map[string]interface{}) (*TermDefinition, error) {
+	if reservedContextKeywords[term] {
+		return nil, fmt.Errorf("%q is a reserved JSON-LD keyword and cannot be used as a term", term)
+	}
+	td := &TermDefinition{Term: term}
+	for k, v := range value {
+		switch k {
+		case "@id":
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("term %q: @id must be a string", term)
+			}
+			if s == term {
+				return nil, fmt.Errorf("term %q: @id cannot refer back to its own term (cyclic term definition)", term)
+			}
+			td.ID = s
+		case "@type":
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("term %q: @type must be a string", term)
+			}
+			td.Type = s
+		case "@container":
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("term %q: @container must be a string", term)
+			}
+			c, err := parseContainer(s)
+			if err != nil {
+				return nil, fmt.Errorf("term %q: %w", term, err)
+			}
+			td.Container = c
+		case "@language":
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("term %q: @language must be a string", term)
+			}
+			td.Language = s
+		default:
+			return nil, fmt.Errorf("term %q: unrecognized term definition keyword %q", term, k)
+		}
+	}
+	return td, nil
+}
+
+// termDefinitionNode is an RDFNode that makes a single JSON-LD 1.1 term
+// definition available on the ParsingContext for the duration of its
+// term's scope, so other RDFNodes' Apply implementations can consult its
+// @id, @type, and @container coercion via ParsingContext.TermFor.
+type termDefinitionNode struct {
+	def *TermDefinition
+}
+
+func (t *termDefinitionNode) Enter(key string, ctx *ParsingContext) (bool, error) {
+	if key != t.def.Term {
+		return false, nil
+	}
+	ctx.PushTerm(key, t.def)
+	return true, nil
+}
+
+func (t *termDefinitionNode) Exit(key string, ctx *ParsingContext) (bool, error) {
+	if key != t.def.Term {
+		return false, nil
+	}
+	ctx.PopTerm(key)
+	return true, nil
+}
+
+func (t *termDefinitionNode) Apply(key string, value interface{}, ctx *ParsingContext) (bool, error) {
+	return false, nil
+}
+
+// termCoercionNode is the well-known RDFNode (see jsonLDNodes) that actually
+// consumes the @id/@type coercion a termDefinitionNode puts in scope: for a
+// key with an active "@id"-typed term definition, it expands the key's
+// string value to the IRI a VocabularyReference-shaped ctx.Current expects,
+// instead of leaving the raw, uncoerced JSON-LD value for the vocabulary's
+// own node to misinterpret as a literal.
+//
+// It never claims Enter/Exit -- those stay with termDefinitionNode, which
+// is what pushes and pops the term definition this node reads.
+type termCoercionNode struct{}
+
+func (termCoercionNode) Enter(key string, ctx *ParsingContext) (bool, error) { return false, nil }
+func (termCoercionNode) Exit(key string, ctx *ParsingContext) (bool, error)  { return false, nil }
+
+func (termCoercionNode) Apply(key string, value interface{}, ctx *ParsingContext) (bool, error) {
+	td := ctx.TermFor(key)
+	if td == nil || td.Type != "@id" {
+		return false, nil
+	}
+	us, ok := ctx.Current.(URISetter)
+	if !ok {
+		return false, nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return true, fmt.Errorf("term %q: @id-coerced value must be a string IRI, got %T", key, value)
+	}
+	if err := us.SetURI(s); err != nil {
+		return true, fmt.Errorf("term %q: invalid @id-coerced IRI %q: %w", key, s, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,53 @@
+package rdf
+
+import "testing"
+
+func TestParseTermDefinition(t *testing.T) {
+	td, err := parseTermDefinition("actor", map[string]interface{}{
+		"@id":        "as:actor",
+		"@type":      "@id",
+		"@container": "@set",
+	})
+	if err != nil {
+		t.Fatalf("parseTermDefinition returned error: %v", err)
+	}
+	if td.ID != "as:actor" || td.Type != "@id" || td.Container != ContainerSet {
+		t.Errorf("got %+v, want ID=as:actor Type=@id Container=ContainerSet", td)
+	}
+}
+
+func TestParseTermDefinitionRejectsVocabAndBase(t *testing.T) {
+	for _, kw := range []string{"@vocab", "@base"} {
+		if _, err := parseTermDefinition("actor", map[string]interface{}{kw: "x"}); err == nil {
+			t.Errorf("expected %q inside a term definition object to be rejected, got no error", kw)
+		}
+	}
+}
+
+func TestTermCoercionNodeAppliesIDCoercion(t *testing.T) {
+	ctx := &ParsingContext{Current: &VocabularyReference{}}
+	ctx.PushTerm("actor", &TermDefinition{Term: "actor", Type: "@id"})
+
+	applied, err := termCoercionNode{}.Apply("actor", "https://example.com/actors/1", ctx)
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if !applied {
+		t.Fatal("expected an @id-coerced key with a URISetter Current to be applied")
+	}
+	ref := ctx.Current.(*VocabularyReference)
+	if ref.URI == nil || ref.URI.String() != "https://example.com/actors/1" {
+		t.Errorf("got URI %v, want https://example.com/actors/1", ref.URI)
+	}
+}
+
+func TestTermCoercionNodeDefersWithoutActiveTerm(t *testing.T) {
+	ctx := &ParsingContext{Current: &VocabularyReference{}}
+	applied, err := termCoercionNode{}.Apply("actor", "https://example.com/actors/1", ctx)
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	if applied {
+		t.Fatal("expected termCoercionNode to defer to other nodes when no term definition is active")
+	}
+}
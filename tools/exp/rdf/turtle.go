@@ -0,0 +1,178 @@
+package rdf
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WriteTurtle serializes v as RDF/Turtle, describing every VocabularyType,
+// VocabularyProperty, and VocabularyValue it defines along with their
+// Extends/DisjointWith/Domain/Range/SubpropertyOf relationships. Cross-
+// vocabulary VocabularyReference values are resolved to a `prefix:name`
+// CURIE via the vocab names found in v.References. A VocabularyValue's
+// DefinitionType -- the concrete Go type backing it -- round-trips as a
+// `this:definitionType` literal.
+func WriteTurtle(w io.Writer, v *ParsedVocabulary) error {
+	prefixes := namespacePrefixes(v)
+	if err := writeTurtlePrefixes(w, prefixes); err != nil {
+		return err
+	}
+	for _, name := range sortedTypeNames(v.Vocab.Types) {
+		if err := writeTurtleType(w, v.Vocab.Types[name], prefixes); err != nil {
+			return fmt.Errorf("writing turtle for type %q: %w", name, err)
+		}
+	}
+	for _, name := range sortedPropertyNames(v.Vocab.Properties) {
+		if err := writeTurtleProperty(w, v.Vocab.Properties[name], prefixes); err != nil {
+			return fmt.Errorf("writing turtle for property %q: %w", name, err)
+		}
+	}
+	for _, name := range sortedValueNames(v.Vocab.Values) {
+		if err := writeTurtleValue(w, v.Vocab.Values[name]); err != nil {
+			return fmt.Errorf("writing turtle for value %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func writeTurtlePrefixes(w io.Writer, prefixes map[string]string) error {
+	if _, err := fmt.Fprint(w, "@prefix this: <#> .\n@prefix owl: <http://www.w3.org/2002/07/owl#> .\n@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .\n@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .\n"); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(prefixes))
+	for name := range prefixes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := fmt.Fprintf(w, "@prefix %s: <%s#> .\n", prefixes[name], name); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprint(w, "\n")
+	return err
+}
+
+func writeTurtleType(w io.Writer, t VocabularyType, prefixes map[string]string) error {
+	if _, err := fmt.Fprintf(w, "this:%s a rdfs:Class", t.Name); err != nil {
+		return err
+	}
+	for _, ext := range t.Extends {
+		if _, err := fmt.Fprintf(w, " ;\n\trdfs:subClassOf %s", curie(ext, prefixes)); err != nil {
+			return err
+		}
+	}
+	for _, dj := range t.DisjointWith {
+		if _, err := fmt.Fprintf(w, " ;\n\towl:disjointWith %s", curie(dj, prefixes)); err != nil {
+			return err
+		}
+	}
+	if t.Notes != "" {
+		if _, err := fmt.Fprintf(w, " ;\n\trdfs:comment %s", turtleString(t.Notes)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprint(w, " .\n\n")
+	return err
+}
+
+func writeTurtleProperty(w io.Writer, p VocabularyProperty, prefixes map[string]string) error {
+	kind := "owl:ObjectProperty"
+	if p.Functional {
+		kind = "owl:FunctionalProperty"
+	}
+	if _, err := fmt.Fprintf(w, "this:%s a %s", p.Name, kind); err != nil {
+		return err
+	}
+	for _, d := range p.Domain {
+		if _, err := fmt.Fprintf(w, " ;\n\trdfs:domain %s", curie(d, prefixes)); err != nil {
+			return err
+		}
+	}
+	for _, r := range p.Range {
+		if _, err := fmt.Fprintf(w, " ;\n\trdfs:range %s", curie(r, prefixes)); err != nil {
+			return err
+		}
+	}
+	if p.NaturalLanguageMap {
+		if _, err := fmt.Fprint(w, " ;\n\trdfs:range rdf:langString"); err != nil {
+			return err
+		}
+	}
+	if p.SubpropertyOf.Name != "" {
+		if _, err := fmt.Fprintf(w, " ;\n\trdfs:subPropertyOf %s", curie(p.SubpropertyOf, prefixes)); err != nil {
+			return err
+		}
+	}
+	if p.Notes != "" {
+		if _, err := fmt.Fprintf(w, " ;\n\trdfs:comment %s", turtleString(p.Notes)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprint(w, " .\n\n")
+	return err
+}
+
+// writeTurtleValue serializes a VocabularyValue as an rdfs:Datatype,
+// recording its DefinitionType -- the Go type a code generator backs it
+// with -- as a `this:definitionType` literal so it round-trips.
+func writeTurtleValue(w io.Writer, val VocabularyValue) error {
+	if _, err := fmt.Fprintf(w, "this:%s a rdfs:Datatype", val.Name); err != nil {
+		return err
+	}
+	if val.DefinitionType != "" {
+		if _, err := fmt.Fprintf(w, " ;\n\tthis:definitionType %s", turtleString(val.DefinitionType)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprint(w, " .\n\n")
+	return err
+}
+
+// turtleString escapes s for use as a Turtle string literal.
+func turtleString(s string) string {
+	escaped := make([]byte, 0, len(s)+2)
+	escaped = append(escaped, '"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			escaped = append(escaped, '\\', '"')
+		case '\\':
+			escaped = append(escaped, '\\', '\\')
+		case '\n':
+			escaped = append(escaped, '\\', 'n')
+		default:
+			escaped = append(escaped, string(r)...)
+		}
+	}
+	escaped = append(escaped, '"')
+	return string(escaped)
+}
+
+func sortedTypeNames(m map[string]VocabularyType) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedPropertyNames(m map[string]VocabularyProperty) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedValueNames(m map[string]VocabularyValue) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
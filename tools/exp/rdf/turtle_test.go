@@ -0,0 +1,27 @@
+package rdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteTurtleEmitsVocabularyValues(t *testing.T) {
+	v := &ParsedVocabulary{
+		Vocab: Vocabulary{
+			Values: map[string]VocabularyValue{
+				"PositiveInteger": {Name: "PositiveInteger", DefinitionType: "uint64"},
+			},
+		},
+	}
+	var b strings.Builder
+	if err := WriteTurtle(&b, v); err != nil {
+		t.Fatalf("WriteTurtle returned error: %v", err)
+	}
+	out := b.String()
+	if !strings.Contains(out, "this:PositiveInteger a rdfs:Datatype") {
+		t.Errorf("output missing value declaration:\n%s", out)
+	}
+	if !strings.Contains(out, `this:definitionType "uint64"`) {
+		t.Errorf("output missing definition type literal:\n%s", out)
+	}
+}